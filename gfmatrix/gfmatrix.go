@@ -0,0 +1,308 @@
+// Copyright 2014 Steve Kaliski.
+
+// Package gfmatrix implements matrices over GF(2^8), the finite field
+// used by Reed-Solomon erasure coding. It mirrors the API of the
+// top-level matrix package, but operates on byte elements instead of
+// float64.
+package gfmatrix
+
+import "errors"
+
+var (
+	errPositiveNumberRequired = errors.New("positive number required")
+	errIndexOutOfRange        = errors.New("index out of range")
+	errDimensionMismatch      = errors.New("matrices dimensions do not match")
+	errIsNotSquare            = errors.New("must be a n x n matrix")
+	errRowsMustBeSameSize     = errors.New("rows must contain the same number of elements")
+	errSingular               = errors.New("matrix is singular")
+)
+
+// primitivePoly is the Rijndael/Reed-Solomon primitive polynomial for
+// GF(2^8): x^8 + x^4 + x^3 + x^2 + 1.
+const primitivePoly = 0x11D
+
+// logTable and expTable implement GF(2^8) multiplication as
+// mul(a, b) = expTable[logTable[a]+logTable[b]], avoiding the need for
+// repeated polynomial reduction. expTable is double-length so that the
+// sum of two logs (each at most 254) never needs to wrap.
+var (
+	logTable [256]byte
+	expTable [512]byte
+)
+
+func init() {
+	x := 1
+	for i := 0; i < 255; i++ {
+		expTable[i] = byte(x)
+		logTable[x] = byte(i)
+
+		x <<= 1
+		if x&0x100 != 0 {
+			x ^= primitivePoly
+		}
+	}
+
+	for i := 255; i < 512; i++ {
+		expTable[i] = expTable[i-255]
+	}
+}
+
+// mul multiplies two GF(2^8) elements.
+func mul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return expTable[int(logTable[a])+int(logTable[b])]
+}
+
+// inv returns the multiplicative inverse of a nonzero GF(2^8) element.
+func inv(a byte) byte {
+	return expTable[255-int(logTable[a])]
+}
+
+// GFMatrix defines a two-dimensional matrix over GF(2^8), comprised of
+// rows and columns of byte elements.
+type GFMatrix struct {
+	data [][]byte
+}
+
+// New creates a new matrix over GF(2^8).
+func New(data [][]byte) (*GFMatrix, error) {
+	var numRow = len(data)
+
+	if numRow == 0 {
+		return nil, errPositiveNumberRequired
+	}
+
+	if len(data[0]) == 0 {
+		return nil, errPositiveNumberRequired
+	}
+
+	numCol := len(data[0])
+	for _, row := range data {
+		if len(row) != numCol {
+			return nil, errRowsMustBeSameSize
+		}
+	}
+
+	return &GFMatrix{data: data}, nil
+}
+
+// NewIdentity creates a new n x n identity matrix over GF(2^8).
+func NewIdentity(n int) (*GFMatrix, error) {
+	if n <= 0 {
+		return nil, errPositiveNumberRequired
+	}
+
+	data := make([][]byte, n)
+	for i := 0; i < n; i++ {
+		data[i] = make([]byte, n)
+		if i < n {
+			data[i][i] = 1
+		}
+	}
+
+	return New(data)
+}
+
+// Vandermonde builds a rows x cols Vandermonde matrix over GF(2^8),
+// where element (i, j) = i^j (with 0^0 = 1). This is the standard
+// starting point for a Reed-Solomon generator matrix.
+func Vandermonde(rows, cols int) (*GFMatrix, error) {
+	if rows <= 0 || cols <= 0 {
+		return nil, errPositiveNumberRequired
+	}
+
+	data := make([][]byte, rows)
+	for i := 0; i < rows; i++ {
+		data[i] = make([]byte, cols)
+		power := byte(1)
+		for j := 0; j < cols; j++ {
+			data[i][j] = power
+			power = mul(power, byte(i))
+		}
+	}
+
+	return New(data)
+}
+
+// ReedSolomonGenerator builds a (rows)x(dataShards) Reed-Solomon
+// generator matrix: a Vandermonde matrix whose top dataShards x
+// dataShards square is inverted and multiplied through the whole
+// matrix, so that the first dataShards rows form the identity and the
+// remaining rows produce parity shards.
+func ReedSolomonGenerator(rows, dataShards int) (*GFMatrix, error) {
+	vm, err := Vandermonde(rows, dataShards)
+	if err != nil {
+		return nil, err
+	}
+
+	top, err := vm.top(dataShards)
+	if err != nil {
+		return nil, err
+	}
+
+	topInv, err := top.Invert()
+	if err != nil {
+		return nil, err
+	}
+
+	return vm.Multiply(topInv)
+}
+
+// top returns the top n x n square of the matrix.
+func (m *GFMatrix) top(n int) (*GFMatrix, error) {
+	if n > m.GetRowCount() {
+		return nil, errDimensionMismatch
+	}
+
+	data := make([][]byte, n)
+	for i := 0; i < n; i++ {
+		data[i] = append([]byte(nil), m.data[i][:n]...)
+	}
+
+	return New(data)
+}
+
+// GetRowCount determines the total number of rows in the matrix.
+func (m *GFMatrix) GetRowCount() int {
+	return len(m.data)
+}
+
+// GetColumnCount determines the number of columns in the matrix.
+func (m *GFMatrix) GetColumnCount() int {
+	return len(m.data[0])
+}
+
+// GetElement retrieves an element from the matrix.
+func (m *GFMatrix) GetElement(i, j int) (byte, error) {
+	if (i < 0 || i >= m.GetRowCount()) || (j < 0 || j >= m.GetColumnCount()) {
+		return 0, errIndexOutOfRange
+	}
+
+	return m.data[i][j], nil
+}
+
+// SetElement sets an element in the matrix.
+func (m *GFMatrix) SetElement(i, j int, val byte) error {
+	if (i < 0 || i >= m.GetRowCount()) || (j < 0 || j >= m.GetColumnCount()) {
+		return errIndexOutOfRange
+	}
+
+	m.data[i][j] = val
+	return nil
+}
+
+// IsSquare determines if the matrix is an n x n matrix.
+func (m *GFMatrix) IsSquare() bool {
+	return m.GetRowCount() == m.GetColumnCount()
+}
+
+// IsSameSize determines if a matrix has the same dimensions as another.
+func (m *GFMatrix) IsSameSize(b *GFMatrix) bool {
+	return m.GetRowCount() == b.GetRowCount() &&
+		m.GetColumnCount() == b.GetColumnCount()
+}
+
+// Add adds two matrices together. Addition in GF(2^8) is XOR.
+func (m *GFMatrix) Add(b *GFMatrix) error {
+	if !m.IsSameSize(b) {
+		return errDimensionMismatch
+	}
+
+	for i := 0; i < m.GetRowCount(); i++ {
+		for j := 0; j < m.GetColumnCount(); j++ {
+			m.data[i][j] ^= b.data[i][j]
+		}
+	}
+
+	return nil
+}
+
+// Scale executes scalar multiplication on a matrix over GF(2^8).
+func (m *GFMatrix) Scale(val byte) {
+	for i := 0; i < m.GetRowCount(); i++ {
+		for j := 0; j < m.GetColumnCount(); j++ {
+			m.data[i][j] = mul(m.data[i][j], val)
+		}
+	}
+}
+
+// Multiply returns the matrix product m*b over GF(2^8).
+func (m *GFMatrix) Multiply(b *GFMatrix) (*GFMatrix, error) {
+	if m.GetColumnCount() != b.GetRowCount() {
+		return nil, errDimensionMismatch
+	}
+
+	rows := m.GetRowCount()
+	cols := b.GetColumnCount()
+	inner := m.GetColumnCount()
+
+	data := make([][]byte, rows)
+	for i := 0; i < rows; i++ {
+		data[i] = make([]byte, cols)
+		for j := 0; j < cols; j++ {
+			var sum byte
+			for k := 0; k < inner; k++ {
+				sum ^= mul(m.data[i][k], b.data[k][j])
+			}
+			data[i][j] = sum
+		}
+	}
+
+	return New(data)
+}
+
+// Invert computes the inverse of the matrix using Gauss-Jordan
+// elimination on the augmented [A|I] matrix over GF(2^8).
+func (m *GFMatrix) Invert() (*GFMatrix, error) {
+	if !m.IsSquare() {
+		return nil, errIsNotSquare
+	}
+
+	n := m.GetRowCount()
+
+	aug := make([][]byte, n)
+	for i := 0; i < n; i++ {
+		aug[i] = make([]byte, 2*n)
+		copy(aug[i], m.data[i])
+		aug[i][n+i] = 1
+	}
+
+	for col := 0; col < n; col++ {
+		pivotRow := -1
+		for row := col; row < n; row++ {
+			if aug[row][col] != 0 {
+				pivotRow = row
+				break
+			}
+		}
+		if pivotRow == -1 {
+			return nil, errSingular
+		}
+
+		aug[col], aug[pivotRow] = aug[pivotRow], aug[col]
+
+		pivotInv := inv(aug[col][col])
+		for j := 0; j < 2*n; j++ {
+			aug[col][j] = mul(aug[col][j], pivotInv)
+		}
+
+		for row := 0; row < n; row++ {
+			if row == col || aug[row][col] == 0 {
+				continue
+			}
+			factor := aug[row][col]
+			for j := 0; j < 2*n; j++ {
+				aug[row][j] ^= mul(factor, aug[col][j])
+			}
+		}
+	}
+
+	data := make([][]byte, n)
+	for i := 0; i < n; i++ {
+		data[i] = aug[i][n:]
+	}
+
+	return New(data)
+}
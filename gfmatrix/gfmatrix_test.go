@@ -0,0 +1,160 @@
+// Copyright 2014 Steve Kaliski.
+
+package gfmatrix
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewValid(t *testing.T) {
+	_, err := New([][]byte{{1, 2}, {3, 4}})
+	if err != nil {
+		t.Error("unexpected result", err)
+	}
+}
+
+func TestNewInvalid(t *testing.T) {
+	_, err := New([][]byte{{1, 2}, {3, 4, 5}})
+	if err != errRowsMustBeSameSize {
+		t.Error("expected error")
+	}
+}
+
+func TestNewIdentity(t *testing.T) {
+	m, err := NewIdentity(3)
+	if err != nil {
+		t.Error(err)
+	}
+	if !m.IsSquare() {
+		t.Error("failed to create n x n matrix")
+	}
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			v, _ := m.GetElement(i, j)
+			if i == j && v != 1 {
+				t.Error("expected 1 on diagonal")
+			}
+			if i != j && v != 0 {
+				t.Error("expected 0 off diagonal")
+			}
+		}
+	}
+}
+
+func TestMul(t *testing.T) {
+	// 3 * 7 = 0x09 in GF(2^8) with the 0x11D primitive polynomial.
+	if mul(3, 7) != 0x09 {
+		t.Error("unexpected multiplication result")
+	}
+}
+
+func TestInv(t *testing.T) {
+	for a := 1; a < 256; a++ {
+		if mul(byte(a), inv(byte(a))) != 1 {
+			t.Error("inverse did not satisfy a * inv(a) == 1", a)
+		}
+	}
+}
+
+func TestAdd(t *testing.T) {
+	a, _ := New([][]byte{{1, 2}, {3, 4}})
+	b, _ := New([][]byte{{5, 6}, {7, 8}})
+	if err := a.Add(b); err != nil {
+		t.Error(err)
+	}
+
+	want, _ := New([][]byte{{1 ^ 5, 2 ^ 6}, {3 ^ 7, 4 ^ 8}})
+	if !reflect.DeepEqual(a.data, want.data) {
+		t.Error("failed to add matrices correctly")
+	}
+}
+
+func TestScale(t *testing.T) {
+	a, _ := New([][]byte{{1, 2}, {3, 4}})
+	a.Scale(2)
+
+	want := []byte{mul(1, 2), mul(2, 2)}
+	row0, _ := a.GetElement(0, 0)
+	row1, _ := a.GetElement(0, 1)
+	if row0 != want[0] || row1 != want[1] {
+		t.Error("failed to properly scale matrix")
+	}
+}
+
+func TestMultiply(t *testing.T) {
+	a, _ := NewIdentity(3)
+	b, _ := New([][]byte{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}})
+
+	product, err := a.Multiply(b)
+	if err != nil {
+		t.Error(err)
+	}
+	if !reflect.DeepEqual(product.data, b.data) {
+		t.Error("multiplying by the identity should return the original matrix")
+	}
+}
+
+func TestInvert(t *testing.T) {
+	m, _ := New([][]byte{{1, 1, 1}, {1, 2, 3}, {1, 3, 5}})
+
+	inverse, err := m.Invert()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	product, err := m.Multiply(inverse)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	identity, _ := NewIdentity(3)
+	if !reflect.DeepEqual(product.data, identity.data) {
+		t.Error("m * inverse(m) should equal the identity matrix")
+	}
+}
+
+func TestInvertSingular(t *testing.T) {
+	m, _ := New([][]byte{{1, 2}, {2, 4}})
+	if _, err := m.Invert(); err != errSingular {
+		t.Error("expected errSingular")
+	}
+}
+
+func TestVandermonde(t *testing.T) {
+	vm, err := Vandermonde(4, 2)
+	if err != nil {
+		t.Error(err)
+	}
+	if vm.GetRowCount() != 4 || vm.GetColumnCount() != 2 {
+		t.Error("unexpected dimensions")
+	}
+
+	// Row i should be [1, i].
+	for i := 0; i < 4; i++ {
+		v0, _ := vm.GetElement(i, 0)
+		v1, _ := vm.GetElement(i, 1)
+		if v0 != 1 || v1 != byte(i) {
+			t.Error("unexpected row", i, v0, v1)
+		}
+	}
+}
+
+func TestReedSolomonGenerator(t *testing.T) {
+	gen, err := ReedSolomonGenerator(5, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gen.GetRowCount() != 5 || gen.GetColumnCount() != 3 {
+		t.Error("unexpected dimensions")
+	}
+
+	identity, _ := NewIdentity(3)
+	top, err := gen.top(3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(top.data, identity.data) {
+		t.Error("expected top square of generator matrix to be the identity")
+	}
+}
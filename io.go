@@ -0,0 +1,183 @@
+// Copyright 2014 Steve Kaliski.
+
+package matrix
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Parse parses the whitespace/newline form produced by String: rows
+// are separated by newlines and columns by whitespace, e.g.
+// "1 2 3\n4 5 6". It returns errRowsMustBeSameSize for ragged input.
+func Parse(s string) (*Dense, error) {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+
+	data := make([][]float64, 0, len(lines))
+	numCol := 0
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		if numCol == 0 {
+			numCol = len(fields)
+		} else if len(fields) != numCol {
+			return nil, errRowsMustBeSameSize
+		}
+
+		row := make([]float64, len(fields))
+		for i, f := range fields {
+			v, err := strconv.ParseFloat(f, 64)
+			if err != nil {
+				return nil, err
+			}
+			row[i] = v
+		}
+		data = append(data, row)
+	}
+
+	return New(data)
+}
+
+// String returns the matrix in the whitespace/newline form accepted by
+// Parse.
+func (m *Dense) String() string {
+	return m.format(-1)
+}
+
+// Format implements fmt.Formatter so that %v prints the matrix with
+// aligned columns, and a precision such as %.3f is honored for each
+// element.
+func (m *Dense) Format(f fmt.State, verb rune) {
+	prec := -1
+	if p, ok := f.Precision(); ok {
+		prec = p
+	}
+	io.WriteString(f, m.format(prec))
+}
+
+// format renders the matrix as whitespace-separated, newline-delimited
+// rows with columns right-aligned to a common width. prec of -1 uses
+// the shortest representation that round-trips.
+func (m *Dense) format(prec int) string {
+	strs := make([][]string, m.rows)
+	width := 0
+	for i := 0; i < m.rows; i++ {
+		strs[i] = make([]string, m.cols)
+		for j := 0; j < m.cols; j++ {
+			var s string
+			if prec >= 0 {
+				s = strconv.FormatFloat(m.At(i, j), 'f', prec, 64)
+			} else {
+				s = strconv.FormatFloat(m.At(i, j), 'g', -1, 64)
+			}
+			strs[i][j] = s
+			if len(s) > width {
+				width = len(s)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.cols; j++ {
+			if j > 0 {
+				buf.WriteByte(' ')
+			}
+			fmt.Fprintf(&buf, "%*s", width, strs[i][j])
+		}
+		if i < m.rows-1 {
+			buf.WriteByte('\n')
+		}
+	}
+
+	return buf.String()
+}
+
+// denseJSON is the wire format used by MarshalJSON/UnmarshalJSON.
+type denseJSON struct {
+	Rows int         `json:"rows"`
+	Cols int         `json:"cols"`
+	Data [][]float64 `json:"data"`
+}
+
+// MarshalJSON implements json.Marshaler, producing
+// {"rows":r,"cols":c,"data":[[...],[...]]}.
+func (m *Dense) MarshalJSON() ([]byte, error) {
+	data := make([][]float64, m.rows)
+	for i := range data {
+		data[i], _ = m.GetRow(i)
+	}
+
+	return json.Marshal(denseJSON{Rows: m.rows, Cols: m.cols, Data: data})
+}
+
+// UnmarshalJSON implements json.Unmarshaler for the format produced by
+// MarshalJSON.
+func (m *Dense) UnmarshalJSON(b []byte) error {
+	var dj denseJSON
+	if err := json.Unmarshal(b, &dj); err != nil {
+		return err
+	}
+
+	nm, err := New(dj.Data)
+	if err != nil {
+		return err
+	}
+
+	*m = *nm
+	return nil
+}
+
+// ReadCSV reads a matrix from r in CSV form, one row per line.
+func ReadCSV(r io.Reader) (*Dense, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([][]float64, len(records))
+	for i, record := range records {
+		data[i] = make([]float64, len(record))
+		for j, field := range record {
+			v, err := strconv.ParseFloat(field, 64)
+			if err != nil {
+				return nil, err
+			}
+			data[i][j] = v
+		}
+	}
+
+	return New(data)
+}
+
+// WriteCSV writes the matrix to w in CSV form, one row per line.
+func (m *Dense) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+
+	for i := 0; i < m.rows; i++ {
+		row, err := m.GetRow(i)
+		if err != nil {
+			return err
+		}
+
+		record := make([]string, len(row))
+		for j, v := range row {
+			record[j] = strconv.FormatFloat(v, 'g', -1, 64)
+		}
+
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,98 @@
+// Copyright 2014 Steve Kaliski.
+
+package matrix
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	m, err := Parse("1 2 3\n4 5 6")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, _ := New([][]float64{{1, 2, 3}, {4, 5, 6}})
+	if !m.IsEqual(want) {
+		t.Error("failed to parse matrix correctly")
+	}
+}
+
+func TestParseRagged(t *testing.T) {
+	_, err := Parse("1 2 3\n4 5")
+	if err != errRowsMustBeSameSize {
+		t.Error("expected errRowsMustBeSameSize")
+	}
+}
+
+func TestParseStringRoundTrip(t *testing.T) {
+	m, _ := New([][]float64{{1, 2, 3}, {4, 5, 6}})
+
+	reparsed, err := Parse(m.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !m.IsEqual(reparsed) {
+		t.Error("round trip through String/Parse should preserve the matrix")
+	}
+}
+
+func TestFormatPrecision(t *testing.T) {
+	m, _ := New([][]float64{{1, 2}, {3, 4}})
+
+	got := fmt.Sprintf("%.3f", m)
+	if !strings.Contains(got, "1.000") {
+		t.Error("expected fixed precision to be honored", got)
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	m, _ := New([][]float64{{1, 2}, {3, 4}})
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var dj denseJSON
+	if err := json.Unmarshal(b, &dj); err != nil {
+		t.Fatal(err)
+	}
+	if dj.Rows != 2 || dj.Cols != 2 {
+		t.Error("unexpected dimensions in marshaled JSON", dj.Rows, dj.Cols)
+	}
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	var m Dense
+	err := json.Unmarshal([]byte(`{"rows":2,"cols":2,"data":[[1,2],[3,4]]}`), &m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, _ := New([][]float64{{1, 2}, {3, 4}})
+	if !m.IsEqual(want) {
+		t.Error("failed to unmarshal matrix correctly")
+	}
+}
+
+func TestCSVRoundTrip(t *testing.T) {
+	m, _ := New([][]float64{{1, 2, 3}, {4, 5, 6}})
+
+	var buf bytes.Buffer
+	if err := m.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	reread, err := ReadCSV(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !m.IsEqual(reread) {
+		t.Error("round trip through WriteCSV/ReadCSV should preserve the matrix")
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright 2014 Steve Kaliski.
+
+package matrix
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrSingular is returned when an operation requires a matrix to be
+// non-singular (invertible) but it is not, within eps tolerance.
+var ErrSingular = errors.New("matrix is singular")
+
+// eps is the tolerance below which a pivot is considered zero.
+const eps = 1e-12
+
+// LU holds the result of an LU decomposition with partial pivoting,
+// such that PA = LU. U is stored in the upper triangle (including the
+// diagonal) of lu, and the multipliers that make up L (below the unit
+// diagonal) are stored in the lower triangle.
+type LU struct {
+	lu   *Dense
+	perm []int
+	sign float64
+}
+
+// LU computes the LU decomposition of the matrix with partial pivoting:
+// PA = LU, where L is unit lower-triangular, U is upper-triangular, and
+// P is a row permutation. The matrix must be square.
+func (m *Dense) LU() (*LU, error) {
+	if !m.IsSquare() {
+		return nil, errIsNotSquare
+	}
+
+	n := m.rows
+	data := make([]float64, n*n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			data[i*n+j] = m.At(i, j)
+		}
+	}
+
+	perm := make([]int, n)
+	for i := range perm {
+		perm[i] = i
+	}
+	sign := 1.0
+
+	at := func(i, j int) float64 { return data[i*n+j] }
+	set := func(i, j int, v float64) { data[i*n+j] = v }
+	swapRows := func(a, b int) {
+		for j := 0; j < n; j++ {
+			data[a*n+j], data[b*n+j] = data[b*n+j], data[a*n+j]
+		}
+	}
+
+	for k := 0; k < n; k++ {
+		pivotRow := k
+		maxVal := math.Abs(at(k, k))
+		for i := k + 1; i < n; i++ {
+			if v := math.Abs(at(i, k)); v > maxVal {
+				maxVal = v
+				pivotRow = i
+			}
+		}
+
+		if pivotRow != k {
+			swapRows(k, pivotRow)
+			perm[k], perm[pivotRow] = perm[pivotRow], perm[k]
+			sign = -sign
+		}
+
+		if math.Abs(at(k, k)) < eps {
+			continue
+		}
+
+		for i := k + 1; i < n; i++ {
+			mult := at(i, k) / at(k, k)
+			set(i, k, mult)
+			for j := k + 1; j < n; j++ {
+				set(i, j, at(i, j)-mult*at(k, j))
+			}
+		}
+	}
+
+	lu, err := NewDense(n, n, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &LU{lu: lu, perm: perm, sign: sign}, nil
+}
+
+// Determinant returns the determinant of the original matrix, computed
+// as sign * the product of U's diagonal.
+func (l *LU) Determinant() float64 {
+	n := l.lu.rows
+	det := l.sign
+	for i := 0; i < n; i++ {
+		det *= l.lu.At(i, i)
+	}
+	return det
+}
+
+// Solve solves Ax = b for x, using forward substitution against L
+// followed by back substitution against U. It returns ErrSingular if
+// the matrix is singular.
+func (l *LU) Solve(b []float64) ([]float64, error) {
+	n := l.lu.rows
+	if len(b) != n {
+		return nil, errDimensionMismatch
+	}
+
+	for i := 0; i < n; i++ {
+		if math.Abs(l.lu.At(i, i)) < eps {
+			return nil, ErrSingular
+		}
+	}
+
+	// Apply the permutation to b.
+	pb := make([]float64, n)
+	for i, p := range l.perm {
+		pb[i] = b[p]
+	}
+
+	// Forward substitution: Ly = pb, L has an implicit unit diagonal.
+	y := make([]float64, n)
+	for i := 0; i < n; i++ {
+		sum := pb[i]
+		for j := 0; j < i; j++ {
+			sum -= l.lu.At(i, j) * y[j]
+		}
+		y[i] = sum
+	}
+
+	// Back substitution: Ux = y.
+	x := make([]float64, n)
+	for i := n - 1; i >= 0; i-- {
+		sum := y[i]
+		for j := i + 1; j < n; j++ {
+			sum -= l.lu.At(i, j) * x[j]
+		}
+		x[i] = sum / l.lu.At(i, i)
+	}
+
+	return x, nil
+}
+
+// Inverse computes the inverse of the original matrix by solving
+// against each column of the identity. It returns ErrSingular if the
+// matrix is singular.
+func (l *LU) Inverse() (*Dense, error) {
+	n := l.lu.rows
+	inv := make([]float64, n*n)
+
+	e := make([]float64, n)
+	for col := 0; col < n; col++ {
+		if col > 0 {
+			e[col-1] = 0
+		}
+		e[col] = 1
+
+		x, err := l.Solve(e)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := 0; i < n; i++ {
+			inv[i*n+col] = x[i]
+		}
+	}
+
+	return NewDense(n, n, inv)
+}
@@ -0,0 +1,107 @@
+// Copyright 2014 Steve Kaliski.
+
+package matrix
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLUDeterminant2x2(t *testing.T) {
+	m, _ := New([][]float64{{4, 3}, {6, 3}})
+	det, err := m.Determinant()
+	if err != nil {
+		t.Error(err)
+	}
+	if det != -6 {
+		t.Error("unexpected result", det)
+	}
+}
+
+func TestLUDeterminant4x4(t *testing.T) {
+	m, _ := New([][]float64{
+		{5, 2, 1, 3},
+		{1, 4, 2, 1},
+		{3, 1, 6, 2},
+		{2, 3, 1, 5},
+	})
+	det, err := m.Determinant()
+	if err != nil {
+		t.Error(err)
+	}
+	if math.Abs(det-354) > 1e-9 {
+		t.Error("unexpected result", det)
+	}
+}
+
+func TestLUDeterminantSingular(t *testing.T) {
+	m, _ := New([][]float64{
+		{1, 2, 3},
+		{2, 4, 6},
+		{7, 8, 9},
+	})
+	det, err := m.Determinant()
+	if err != nil {
+		t.Error(err)
+	}
+	if math.Abs(det) > 1e-9 {
+		t.Error("expected a zero determinant for a singular matrix", det)
+	}
+}
+
+func TestLUSolve(t *testing.T) {
+	m, _ := New([][]float64{{2, 1}, {1, 3}})
+	lu, err := m.LU()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	x, err := lu.Solve([]float64{3, 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []float64{0.8, 1.4}
+	for i := range want {
+		if math.Abs(x[i]-want[i]) > 1e-9 {
+			t.Error("unexpected result", x)
+		}
+	}
+}
+
+func TestLUSolveSingular(t *testing.T) {
+	m, _ := New([][]float64{{1, 2}, {2, 4}})
+	lu, err := m.LU()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = lu.Solve([]float64{1, 2})
+	if err != ErrSingular {
+		t.Error("expected ErrSingular", err)
+	}
+}
+
+func TestLUInverse(t *testing.T) {
+	m, _ := New([][]float64{{4, 7}, {2, 6}})
+	lu, err := m.LU()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inv, err := lu.Inverse()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, _ := New([][]float64{{0.6, -0.7}, {-0.2, 0.4}})
+	for i := 0; i < 2; i++ {
+		for j := 0; j < 2; j++ {
+			got, _ := inv.GetElement(i, j)
+			exp, _ := want.GetElement(i, j)
+			if math.Abs(got-exp) > 1e-9 {
+				t.Error("unexpected result", i, j, got)
+			}
+		}
+	}
+}
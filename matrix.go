@@ -2,10 +2,7 @@
 
 package matrix
 
-import (
-	"errors"
-	"reflect"
-)
+import "errors"
 
 var (
 	errPositiveNumberRequired = errors.New("positive number required")
@@ -15,16 +12,54 @@ var (
 	errRowsMustBeSameSize     = errors.New("rows must contain the same number of elements")
 )
 
-// Matrix defines a two-dimensional matrix comprised of
-// rows and columns.
-type Matrix struct {
-	data [][]float64
+// Matrix is implemented by anything that can report its dimensions and
+// read back an element by (row, column) index. Besides Dense, this
+// includes views such as Transpose and Slice that share storage with
+// an underlying Dense rather than copying it.
+type Matrix interface {
+	// Dims returns the number of rows and columns.
+	Dims() (r, c int)
+	// At returns the element at row i, column j. It panics if i or j
+	// is out of range.
+	At(i, j int) float64
+	// T returns the transpose of the matrix. The result may be a view
+	// sharing storage with the receiver rather than a copy.
+	T() Matrix
+}
+
+// Dense is a Matrix whose elements are stored contiguously in a single
+// row-major []float64, addressed via a stride rather than as
+// []float64 rows. This keeps rows adjacent in memory for
+// cache-friendly access, and lets views such as Slice and Transpose
+// share storage instead of copying it.
+type Dense struct {
+	data   []float64
+	rows   int
+	cols   int
+	stride int
+	offset int
+}
+
+// NewDense creates a new rows x cols Dense matrix from data, which
+// must hold exactly rows*cols elements in row-major order. It is the
+// primary constructor; New remains available as a [][]float64
+// convenience.
+func NewDense(rows, cols int, data []float64) (*Dense, error) {
+	if rows <= 0 || cols <= 0 {
+		return nil, errPositiveNumberRequired
+	}
+	if len(data) != rows*cols {
+		return nil, errDimensionMismatch
+	}
+
+	return &Dense{data: data, rows: rows, cols: cols, stride: cols}, nil
 }
 
-// New creates a new matrix.
-func New(data [][]float64) (*Matrix, error) {
+// New creates a new matrix by flattening data into contiguous storage.
+// It is kept as a convenience for callers building matrices out of
+// [][]float64; NewDense is the primary constructor.
+func New(data [][]float64) (*Dense, error) {
 	var numRow = len(data)
-	var numCol int
 
 	if numRow == 0 {
 		return nil, errPositiveNumberRequired
@@ -34,181 +69,215 @@ func New(data [][]float64) (*Matrix, error) {
 		return nil, errPositiveNumberRequired
 	}
 
-	numCol = len(data[0])
+	numCol := len(data[0])
+	flat := make([]float64, 0, numRow*numCol)
 	for _, row := range data {
 		if len(row) != numCol {
 			return nil, errRowsMustBeSameSize
 		}
+		flat = append(flat, row...)
 	}
 
-	return &Matrix{data: data}, nil
+	return NewDense(numRow, numCol, flat)
 }
 
 // NewIdentity creates a new n x n identity matrix.
-func NewIdentity(n int) (*Matrix, error) {
+func NewIdentity(n int) (*Dense, error) {
 	if n <= 0 {
 		return nil, errPositiveNumberRequired
 	}
 
-	var data = make([][]float64, n)
-	for i := 0; i < len(data); i++ {
-		data[i] = make([]float64, n)
-		for j := 0; j < len(data[i]); j++ {
-			if i == j {
-				data[i][j] = 1
-			} else {
-				data[i][j] = 0
-			}
-		}
+	data := make([]float64, n*n)
+	for i := 0; i < n; i++ {
+		data[i*n+i] = 1
 	}
 
-	matrix, err := New(data)
-	if err != nil {
-		return nil, err
+	return NewDense(n, n, data)
+}
+
+// Dims returns the number of rows and columns in the matrix.
+func (m *Dense) Dims() (int, int) {
+	return m.rows, m.cols
+}
+
+// At returns the element at row i, column j. It panics if i or j is
+// out of range.
+func (m *Dense) At(i, j int) float64 {
+	if i < 0 || i >= m.rows || j < 0 || j >= m.cols {
+		panic(errIndexOutOfRange)
 	}
+	return m.data[m.offset+i*m.stride+j]
+}
+
+// Set sets the element at row i, column j. It panics if i or j is out
+// of range.
+func (m *Dense) Set(i, j int, val float64) {
+	if i < 0 || i >= m.rows || j < 0 || j >= m.cols {
+		panic(errIndexOutOfRange)
+	}
+	m.data[m.offset+i*m.stride+j] = val
+}
+
+// T returns a view of the matrix with rows and columns swapped. The
+// view shares storage with m; no data is copied.
+func (m *Dense) T() Matrix {
+	return &Transpose{m: m}
+}
+
+// Slice returns the sub-matrix spanning rows [i0, i1) and columns
+// [j0, j1). The result shares storage with m: writes through either
+// one are visible through the other.
+func (m *Dense) Slice(i0, i1, j0, j1 int) *Dense {
+	return &Dense{
+		data:   m.data,
+		rows:   i1 - i0,
+		cols:   j1 - j0,
+		stride: m.stride,
+		offset: m.offset + i0*m.stride + j0,
+	}
+}
+
+// Transpose is a view of a Matrix with rows and columns swapped. It
+// shares storage with the underlying matrix rather than copying it.
+type Transpose struct {
+	m Matrix
+}
 
-	return matrix, nil
+// Dims returns the number of rows and columns in the transposed view.
+func (t *Transpose) Dims() (int, int) {
+	r, c := t.m.Dims()
+	return c, r
+}
+
+// At returns the element at row i, column j of the transposed view.
+func (t *Transpose) At(i, j int) float64 {
+	return t.m.At(j, i)
+}
+
+// T undoes the transpose, returning the original matrix.
+func (t *Transpose) T() Matrix {
+	return t.m
 }
 
 // GetRowCount determines the total number of rows in the matrix.
-func (m *Matrix) GetRowCount() int {
-	return len(m.data)
+func (m *Dense) GetRowCount() int {
+	return m.rows
 }
 
 // GetRow gets the indexed row.
-func (m *Matrix) GetRow(index int) ([]float64, error) {
-	if index < m.GetRowCount() {
-		return m.data[index], nil
+func (m *Dense) GetRow(index int) ([]float64, error) {
+	if index < 0 || index >= m.rows {
+		return nil, errIndexOutOfRange
 	}
 
-	return nil, errIndexOutOfRange
+	row := make([]float64, m.cols)
+	for j := 0; j < m.cols; j++ {
+		row[j] = m.At(index, j)
+	}
+
+	return row, nil
 }
 
 // GetColumnCount determines the number of columns in the matrix.
-func (m *Matrix) GetColumnCount() int {
-	return len(m.data[0])
+func (m *Dense) GetColumnCount() int {
+	return m.cols
 }
 
 // GetColumn gets the indexed column.
-func (m *Matrix) GetColumn(index int) ([]float64, error) {
+func (m *Dense) GetColumn(index int) ([]float64, error) {
 	var column []float64
-	rowCount := m.GetRowCount()
 
-	if index < 0 || index > rowCount {
+	if index < 0 || index >= m.cols {
 		return column, errIndexOutOfRange
 	}
 
-	for i := 0; i < rowCount; i++ {
-		column = append(column, m.data[i][index])
+	for i := 0; i < m.rows; i++ {
+		column = append(column, m.At(i, index))
 	}
 
 	return column, nil
 }
 
 // GetElement retrieves an element from the matrix.
-func (m *Matrix) GetElement(i, j int) (float64, error) {
-	if (i < 0 || i >= m.GetRowCount()) || (j < 0 || j >= m.GetColumnCount()) {
+func (m *Dense) GetElement(i, j int) (float64, error) {
+	if (i < 0 || i >= m.rows) || (j < 0 || j >= m.cols) {
 		return 0, errIndexOutOfRange
 	}
 
-	return m.data[i][j], nil
+	return m.At(i, j), nil
 }
 
 // SetElement sets an element in the matrix.
-func (m *Matrix) SetElement(i, j int, val float64) error {
-	if (i < 0 || i >= m.GetRowCount()) || (j < 0 || j >= m.GetColumnCount()) {
+func (m *Dense) SetElement(i, j int, val float64) error {
+	if (i < 0 || i >= m.rows) || (j < 0 || j >= m.cols) {
 		return errIndexOutOfRange
 	}
 
-	m.data[i][j] = val
+	m.Set(i, j, val)
 	return nil
 }
 
 // Scale executes scalar multiplication on a matrix.
-func (m *Matrix) Scale(val float64) {
-	for i := 0; i < m.GetRowCount(); i++ {
-		for j := 0; j < m.GetColumnCount(); j++ {
-			m.data[i][j] = val * m.data[i][j]
+func (m *Dense) Scale(val float64) {
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.cols; j++ {
+			m.Set(i, j, val*m.At(i, j))
 		}
 	}
 }
 
-// Transpose executes a transposition on the matrix.
-func (m *Matrix) Transpose() {
-	var numRow = m.GetRowCount()
-	var numCol = m.GetColumnCount()
-	var data = make([][]float64, numCol)
-
-	for i := 0; i < numCol; i++ {
-		data[i] = make([]float64, numRow)
-		for j := 0; j < numRow; j++ {
-			data[i][j] = m.data[j][i]
-		}
-	}
-
-	m.data = data
-}
-
-// Determinant calculates the determinant of the matrix.
-// Must be an n x n matrix.
-func (m *Matrix) Determinant() (float64, error) {
+// Determinant calculates the determinant of the matrix via LU
+// decomposition. Must be an n x n matrix.
+func (m *Dense) Determinant() (float64, error) {
 	if !m.IsSquare() {
 		return 0, errIsNotSquare
 	}
 
-	var numCol = m.GetColumnCount()
-	var numRow = m.GetRowCount()
-	var determinant float64
-	var diagLeft float64
-	var diagRight float64
-
-	for j := 0; j < numCol; j++ {
-		diagLeft = m.data[0][j]
-		diagRight = m.data[0][j]
-
-		for i := 0; i < numRow; i++ {
-			diagRight *= m.data[i][(((j+i)%numCol)+numCol)%numCol]
-			diagLeft *= m.data[i][(((j-i)%numCol)+numCol)%numCol]
-		}
-
-		determinant += diagRight - diagLeft
+	lu, err := m.LU()
+	if err != nil {
+		return 0, err
 	}
 
-	return determinant, nil
+	return lu.Determinant(), nil
 }
 
 // IsSquare determines if the matrix is an n x n matrix.
-func (m *Matrix) IsSquare() bool {
-	return m.GetRowCount() == m.GetColumnCount()
+func (m *Dense) IsSquare() bool {
+	return m.rows == m.cols
 }
 
 // IsEqual determines if a matrix is equal to another matrix.
-func (m *Matrix) IsEqual(b *Matrix) bool {
-	return reflect.DeepEqual(m.data, b.data)
+func (m *Dense) IsEqual(b Matrix) bool {
+	if !m.IsSameSize(b) {
+		return false
+	}
+
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.cols; j++ {
+			if m.At(i, j) != b.At(i, j) {
+				return false
+			}
+		}
+	}
+
+	return true
 }
 
-func (m *Matrix) IsSameSize(b *Matrix) bool {
-	return m.GetRowCount() == b.GetRowCount() &&
-		m.GetColumnCount() == b.GetColumnCount()
+// IsSameSize determines if a matrix has the same dimensions as b.
+func (m *Dense) IsSameSize(b Matrix) bool {
+	br, bc := b.Dims()
+	return m.rows == br && m.cols == bc
 }
 
-// Add adds two matrices together.
-func (m *Matrix) Add(b *Matrix) error {
+// Add adds b to m in place.
+func (m *Dense) Add(b Matrix) error {
 	if !m.IsSameSize(b) {
 		return errDimensionMismatch
 	}
 
-	for i := 0; i < m.GetRowCount(); i++ {
-		for j := 0; j < m.GetColumnCount(); j++ {
-			current, err := m.GetElement(i, j)
-			if err != nil {
-				return err
-			}
-			additive, err := b.GetElement(i, j)
-			if err != nil {
-				return err
-			}
-			m.SetElement(i, j, current+additive)
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.cols; j++ {
+			m.Set(i, j, m.At(i, j)+b.At(i, j))
 		}
 	}
 
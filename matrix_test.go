@@ -3,14 +3,15 @@
 package matrix
 
 import (
+	"math"
 	"reflect"
 	"testing"
 )
 
 var (
 	err               error
-	testMatrix        *Matrix
-	testIdentity      *Matrix
+	testMatrix        *Dense
+	testIdentity      *Dense
 	sampleIdentity, _ = New([][]float64{[]float64{1, 0}, []float64{0, 1}})
 	sampleSquare, _   = New([][]float64{[]float64{1, 2, 3}, []float64{4, 5, 6}, []float64{7, 8, 9}})
 )
@@ -102,9 +103,12 @@ func TestScale(t *testing.T) {
 }
 
 func TestTranspose(t *testing.T) {
-	testMatrix.Transpose()
-	row, _ := testMatrix.GetRow(0)
-	if !reflect.DeepEqual(row, []float64{2, 4}) {
+	tr := testMatrix.T()
+	r, c := tr.Dims()
+	if r != 3 || c != 2 {
+		t.Error("unexpected dimensions", r, c)
+	}
+	if tr.At(0, 0) != 2 || tr.At(0, 1) != 4 {
 		t.Error("failed to properly transpose matrix")
 	}
 }
@@ -114,7 +118,7 @@ func TestDeterminantValid(t *testing.T) {
 	if err != nil {
 		t.Error(err)
 	}
-	if det != -15 {
+	if math.Abs(det-(-3)) > 1e-9 {
 		t.Error("unexepted result", det)
 	}
 }
@@ -138,9 +142,32 @@ func TestIsEqual(t *testing.T) {
 	}
 }
 
+func TestSlice(t *testing.T) {
+	m, _ := New([][]float64{
+		{1, 2, 3},
+		{4, 5, 6},
+		{7, 8, 9},
+	})
+
+	sub := m.Slice(1, 3, 1, 3)
+	r, c := sub.Dims()
+	if r != 2 || c != 2 {
+		t.Error("unexpected dimensions", r, c)
+	}
+	if sub.At(0, 0) != 5 || sub.At(1, 1) != 9 {
+		t.Error("unexpected slice contents")
+	}
+
+	// Slice shares storage with m.
+	sub.Set(0, 0, 50)
+	if m.At(1, 1) != 50 {
+		t.Error("slice should share storage with its parent")
+	}
+}
+
 func TestAdd(t *testing.T) {
 	addMatrix, _ := New([][]float64{[]float64{3, 4}, []float64{4, 5}, []float64{5, 6}})
-	err := addMatrix.Add(testMatrix)
+	err := addMatrix.Add(testMatrix.T())
 	if err != nil {
 		t.Error(err)
 	}
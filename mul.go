@@ -0,0 +1,107 @@
+// Copyright 2014 Steve Kaliski.
+
+package matrix
+
+// BlockSize controls the tile size used by Mul's blocked GEMM kernel.
+// Larger matrices benefit from tiling that keeps each A/B panel hot in
+// L1 cache; override this if profiling suggests a different size for
+// your hardware.
+var BlockSize = 64
+
+// Mul returns the matrix product m*b. b may be any Matrix, including a
+// Transpose or Slice view, without it being materialized into a Dense
+// first.
+//
+// For matrices larger than a single block, the computation is tiled
+// (blocked) across the i, j, k loops rather than using a naive triple
+// loop: for each block, the A and B panels are packed into contiguous
+// buffers to avoid the cost of repeated interface dispatch through At
+// and to keep the inner loop over k cache-friendly.
+func (m *Dense) Mul(b Matrix) (*Dense, error) {
+	bRows, bCols := b.Dims()
+	if m.cols != bRows {
+		return nil, errDimensionMismatch
+	}
+
+	n := m.rows
+	inner := m.cols
+	p := bCols
+
+	c := make([]float64, n*p)
+
+	bs := BlockSize
+	if bs <= 0 {
+		bs = n + inner + p
+	}
+
+	aPanel := make([]float64, bs*bs)
+	bPanel := make([]float64, bs*bs)
+
+	for kk := 0; kk < inner; kk += bs {
+		kEnd := min(kk+bs, inner)
+		kLen := kEnd - kk
+
+		for ii := 0; ii < n; ii += bs {
+			iEnd := min(ii+bs, n)
+
+			for i := ii; i < iEnd; i++ {
+				for k := kk; k < kEnd; k++ {
+					aPanel[(i-ii)*kLen+(k-kk)] = m.At(i, k)
+				}
+			}
+
+			for jj := 0; jj < p; jj += bs {
+				jEnd := min(jj+bs, p)
+				jLen := jEnd - jj
+
+				for k := kk; k < kEnd; k++ {
+					for j := jj; j < jEnd; j++ {
+						bPanel[(k-kk)*jLen+(j-jj)] = b.At(k, j)
+					}
+				}
+
+				for i := ii; i < iEnd; i++ {
+					crow := c[i*p+jj : i*p+jEnd]
+					arow := aPanel[(i-ii)*kLen : (i-ii+1)*kLen]
+					for k := 0; k < kLen; k++ {
+						av := arow[k]
+						if av == 0 {
+							continue
+						}
+						brow := bPanel[k*jLen : (k+1)*jLen]
+						for j := 0; j < jLen; j++ {
+							crow[j] += av * brow[j]
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return NewDense(n, p, c)
+}
+
+// MulVec computes y = m*x.
+func (m *Dense) MulVec(x []float64) ([]float64, error) {
+	if m.cols != len(x) {
+		return nil, errDimensionMismatch
+	}
+
+	y := make([]float64, m.rows)
+	for i := 0; i < m.rows; i++ {
+		var sum float64
+		for k := 0; k < m.cols; k++ {
+			sum += m.At(i, k) * x[k]
+		}
+		y[i] = sum
+	}
+
+	return y, nil
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,153 @@
+// Copyright 2014 Steve Kaliski.
+
+package matrix
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestMul(t *testing.T) {
+	a, _ := New([][]float64{{1, 2}, {3, 4}})
+	b, _ := New([][]float64{{5, 6}, {7, 8}})
+
+	product, err := a.Mul(b)
+	if err != nil {
+		t.Error(err)
+	}
+
+	want, _ := New([][]float64{{19, 22}, {43, 50}})
+	if !reflect.DeepEqual(product.data, want.data) {
+		t.Error("failed to multiply matrices correctly")
+	}
+}
+
+func TestMulDimensionMismatch(t *testing.T) {
+	a, _ := New([][]float64{{1, 2, 3}})
+	b, _ := New([][]float64{{1, 2, 3}})
+
+	_, err := a.Mul(b)
+	if err != errDimensionMismatch {
+		t.Error("expected errDimensionMismatch")
+	}
+}
+
+func TestMulIdentity(t *testing.T) {
+	identity, _ := NewIdentity(3)
+	m, _ := New([][]float64{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}})
+
+	product, err := identity.Mul(m)
+	if err != nil {
+		t.Error(err)
+	}
+	if !reflect.DeepEqual(product.data, m.data) {
+		t.Error("multiplying by the identity should return the original matrix")
+	}
+}
+
+// TestMulBlockBoundary exercises a matrix larger than a single block so
+// the tiled loops and panel packing are run on more than one tile.
+func TestMulBlockBoundary(t *testing.T) {
+	old := BlockSize
+	BlockSize = 4
+	defer func() { BlockSize = old }()
+
+	n := 10
+	data := make([][]float64, n)
+	for i := range data {
+		data[i] = make([]float64, n)
+		for j := range data[i] {
+			data[i][j] = float64(i*n + j)
+		}
+	}
+	m, _ := New(data)
+	identity, _ := NewIdentity(n)
+
+	product, err := m.Mul(identity)
+	if err != nil {
+		t.Error(err)
+	}
+	if !reflect.DeepEqual(product.data, m.data) {
+		t.Error("blocked multiply by the identity should return the original matrix")
+	}
+}
+
+func TestMulVec(t *testing.T) {
+	m, _ := New([][]float64{{1, 2}, {3, 4}})
+
+	y, err := m.MulVec([]float64{5, 6})
+	if err != nil {
+		t.Error(err)
+	}
+
+	want := []float64{17, 39}
+	if !reflect.DeepEqual(y, want) {
+		t.Error("unexpected result", y)
+	}
+}
+
+func TestMulVecDimensionMismatch(t *testing.T) {
+	m, _ := New([][]float64{{1, 2}, {3, 4}})
+
+	_, err := m.MulVec([]float64{1, 2, 3})
+	if err != errDimensionMismatch {
+		t.Error("expected errDimensionMismatch")
+	}
+}
+
+func randomMatrix(n int) *Dense {
+	data := make([][]float64, n)
+	for i := range data {
+		data[i] = make([]float64, n)
+		for j := range data[i] {
+			data[i][j] = rand.Float64()
+		}
+	}
+	m, _ := New(data)
+	return m
+}
+
+// naiveMul is the unblocked triple loop, kept here only to benchmark
+// the blocked Mul against.
+func naiveMul(a, b *Dense) (*Dense, error) {
+	if a.GetColumnCount() != b.GetRowCount() {
+		return nil, errDimensionMismatch
+	}
+
+	n := a.GetRowCount()
+	inner := a.GetColumnCount()
+	p := b.GetColumnCount()
+
+	c := make([][]float64, n)
+	for i := 0; i < n; i++ {
+		c[i] = make([]float64, p)
+		for j := 0; j < p; j++ {
+			var sum float64
+			for k := 0; k < inner; k++ {
+				sum += a.At(i, k) * b.At(k, j)
+			}
+			c[i][j] = sum
+		}
+	}
+
+	return New(c)
+}
+
+func BenchmarkMulNaive256(b *testing.B) {
+	x := randomMatrix(256)
+	y := randomMatrix(256)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		naiveMul(x, y)
+	}
+}
+
+func BenchmarkMulBlocked256(b *testing.B) {
+	x := randomMatrix(256)
+	y := randomMatrix(256)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.Mul(y)
+	}
+}
@@ -0,0 +1,320 @@
+// Copyright 2014 Steve Kaliski.
+
+package matrix
+
+import "math"
+
+// QRBlockSize controls the panel width used by QR's blocked Householder
+// factorization. Reflectors within a panel are accumulated into a
+// compact WY representation and applied to the trailing submatrix as a
+// single update, so large matrices factorize via BLAS-3-shaped work
+// instead of one rank-1 update per column.
+var QRBlockSize = 32
+
+// QR holds the result of a Householder QR factorization of an m x n
+// matrix (m >= n): A = QR, where Q is m x m orthogonal and R is m x n
+// upper-triangular. The reflector that produced column k is stored
+// below the diagonal of column k (qr.At(k,k) holds R's diagonal entry),
+// with its scale factor in taus[k].
+type QR struct {
+	qr   *Dense
+	taus []float64
+}
+
+// QR factorizes the matrix via Householder reflections, processed in
+// panels of QRBlockSize columns. Within a panel, reflectors are
+// accumulated into a compact WY representation, Q_block = I - V*T*V^T,
+// and applied to the trailing columns as a single update rather than
+// one reflector at a time. The matrix must have at least as many rows
+// as columns.
+func (m *Dense) QR() (*QR, error) {
+	rows, cols := m.rows, m.cols
+	if rows < cols {
+		return nil, errDimensionMismatch
+	}
+
+	data := make([]float64, rows*cols)
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			data[i*cols+j] = m.At(i, j)
+		}
+	}
+	a, err := NewDense(rows, cols, data)
+	if err != nil {
+		return nil, err
+	}
+
+	taus := make([]float64, cols)
+
+	nb := QRBlockSize
+	if nb <= 0 {
+		nb = cols
+	}
+
+	for p := 0; p < cols; p += nb {
+		panelEnd := min(p+nb, cols)
+		width := panelEnd - p
+		vRows := rows - p
+
+		// v holds the panel's reflectors as columns, vRows x width;
+		// unlike the copy kept below a's diagonal, each reflector's
+		// leading 1 is stored explicitly here, with zeros above it.
+		v := make([]float64, vRows*width)
+
+		for k := p; k < panelEnd; k++ {
+			col := k - p
+
+			x := make([]float64, rows-k)
+			for i := range x {
+				x[i] = a.At(k+i, k)
+			}
+
+			vk, tau, beta := house(x)
+
+			a.Set(k, k, beta)
+			for i := 1; i < len(vk); i++ {
+				a.Set(k+i, k, vk[i])
+			}
+			taus[k] = tau
+
+			for i := range vk {
+				v[(k+i-p)*width+col] = vk[i]
+			}
+
+			// Apply this reflector to the remaining columns of the
+			// panel so each subsequent column's subcolumn reflects
+			// the updates made so far.
+			for j := k + 1; j < panelEnd; j++ {
+				var s float64
+				for i := 0; i < len(vk); i++ {
+					s += vk[i] * a.At(k+i, j)
+				}
+				s *= tau
+				for i := 0; i < len(vk); i++ {
+					a.Set(k+i, j, a.At(k+i, j)-s*vk[i])
+				}
+			}
+		}
+
+		if panelEnd == cols {
+			continue
+		}
+
+		t := buildWYT(v, taus[p:panelEnd], vRows, width)
+		applyWYBlock(a, v, t, p, panelEnd, vRows, width)
+	}
+
+	return &QR{qr: a, taus: taus}, nil
+}
+
+// house computes the Householder reflector that zeros all but the
+// first element of x: v = x + sign(x[0])*||x||*e1, normalized so
+// v[0] == 1. tau = 2/(v^T v) and beta is the value x reflects to
+// (R's diagonal entry).
+func house(x []float64) (v []float64, tau, beta float64) {
+	v = append([]float64(nil), x...)
+
+	var normX float64
+	for _, xi := range x {
+		normX += xi * xi
+	}
+	normX = math.Sqrt(normX)
+
+	if normX == 0 {
+		return v, 0, x[0]
+	}
+
+	sign := 1.0
+	if x[0] < 0 {
+		sign = -1.0
+	}
+	beta = -sign * normX
+	v[0] = x[0] - beta
+
+	if v0 := v[0]; v0 != 0 {
+		for i := range v {
+			v[i] /= v0
+		}
+	}
+
+	var vtv float64
+	for _, vi := range v {
+		vtv += vi * vi
+	}
+	if vtv != 0 {
+		tau = 2 / vtv
+	}
+
+	return v, tau, beta
+}
+
+// buildWYT accumulates the width x width upper-triangular T such that
+// Q_block = I - V*T*V^T, using the standard recursion
+// T_k = [[T_{k-1}, -tau_k*T_{k-1}*V_{:,:k-1}^T*v_k], [0, tau_k]].
+func buildWYT(v []float64, taus []float64, vRows, width int) []float64 {
+	t := make([]float64, width*width)
+
+	for col := 0; col < width; col++ {
+		tau := taus[col]
+		t[col*width+col] = tau
+		if col == 0 {
+			continue
+		}
+
+		vcol := make([]float64, vRows)
+		for i := 0; i < vRows; i++ {
+			vcol[i] = v[i*width+col]
+		}
+
+		z := make([]float64, col)
+		for c := 0; c < col; c++ {
+			var s float64
+			for i := 0; i < vRows; i++ {
+				s += v[i*width+c] * vcol[i]
+			}
+			z[c] = s
+		}
+
+		for r := 0; r < col; r++ {
+			var s float64
+			for c := r; c < col; c++ {
+				s += t[r*width+c] * z[c]
+			}
+			t[r*width+col] = -tau * s
+		}
+	}
+
+	return t
+}
+
+// applyWYBlock applies Q_block^T = I - V*T^T*V^T to the trailing
+// columns [panelEnd, a.cols) of a's rows [p, a.rows) as a single
+// GEMM-shaped update: A -= V*(T^T*(V^T*A)).
+func applyWYBlock(a *Dense, v, t []float64, p, panelEnd, vRows, width int) {
+	trailCols := a.cols - panelEnd
+
+	w := make([]float64, width*trailCols)
+	for c := 0; c < width; c++ {
+		for j := 0; j < trailCols; j++ {
+			var s float64
+			for i := 0; i < vRows; i++ {
+				s += v[i*width+c] * a.At(p+i, panelEnd+j)
+			}
+			w[c*trailCols+j] = s
+		}
+	}
+
+	tw := make([]float64, width*trailCols)
+	for c := 0; c < width; c++ {
+		for j := 0; j < trailCols; j++ {
+			var s float64
+			for r := 0; r <= c; r++ {
+				s += t[r*width+c] * w[r*trailCols+j]
+			}
+			tw[c*trailCols+j] = s
+		}
+	}
+
+	for i := 0; i < vRows; i++ {
+		for j := 0; j < trailCols; j++ {
+			var s float64
+			for c := 0; c < width; c++ {
+				s += v[i*width+c] * tw[c*trailCols+j]
+			}
+			a.Set(p+i, panelEnd+j, a.At(p+i, panelEnd+j)-s)
+		}
+	}
+}
+
+// reflector reconstructs the k-th Householder reflector, with the
+// implicit leading 1 restored.
+func (qr *QR) reflector(k int) []float64 {
+	rows := qr.qr.rows
+	v := make([]float64, rows-k)
+	v[0] = 1
+	for i := 1; i < len(v); i++ {
+		v[i] = qr.qr.At(k+i, k)
+	}
+	return v
+}
+
+// Q returns the m x m orthogonal factor, reconstructed by applying the
+// stored reflectors to the identity.
+func (qr *QR) Q() *Dense {
+	m := qr.qr.rows
+	n := qr.qr.cols
+	q, _ := NewIdentity(m)
+
+	for k := n - 1; k >= 0; k-- {
+		v := qr.reflector(k)
+		tau := qr.taus[k]
+
+		for j := 0; j < m; j++ {
+			var s float64
+			for i, vi := range v {
+				s += vi * q.At(k+i, j)
+			}
+			s *= tau
+			for i, vi := range v {
+				q.Set(k+i, j, q.At(k+i, j)-s*vi)
+			}
+		}
+	}
+
+	return q
+}
+
+// R returns the m x n upper-triangular factor.
+func (qr *QR) R() *Dense {
+	m, n := qr.qr.rows, qr.qr.cols
+	data := make([]float64, m*n)
+
+	for i := 0; i < m && i < n; i++ {
+		for j := i; j < n; j++ {
+			data[i*n+j] = qr.qr.At(i, j)
+		}
+	}
+
+	r, _ := NewDense(m, n, data)
+	return r
+}
+
+// Solve solves the least-squares problem min ||Ax - b|| by applying
+// Q^T to b and back-substituting against R's top n x n block. It
+// returns ErrSingular if R has a zero diagonal entry.
+func (qr *QR) Solve(b []float64) ([]float64, error) {
+	m, n := qr.qr.rows, qr.qr.cols
+	if len(b) != m {
+		return nil, errDimensionMismatch
+	}
+
+	y := append([]float64(nil), b...)
+	for k := 0; k < n; k++ {
+		v := qr.reflector(k)
+		tau := qr.taus[k]
+
+		var s float64
+		for i, vi := range v {
+			s += vi * y[k+i]
+		}
+		s *= tau
+		for i, vi := range v {
+			y[k+i] -= s * vi
+		}
+	}
+
+	x := make([]float64, n)
+	for i := n - 1; i >= 0; i-- {
+		if math.Abs(qr.qr.At(i, i)) < eps {
+			return nil, ErrSingular
+		}
+
+		sum := y[i]
+		for j := i + 1; j < n; j++ {
+			sum -= qr.qr.At(i, j) * x[j]
+		}
+		x[i] = sum / qr.qr.At(i, i)
+	}
+
+	return x, nil
+}
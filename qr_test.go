@@ -0,0 +1,146 @@
+// Copyright 2014 Steve Kaliski.
+
+package matrix
+
+import (
+	"math"
+	"testing"
+)
+
+func isOrthogonal(t *testing.T, q *Dense) {
+	t.Helper()
+
+	n, _ := q.Dims()
+	qt := q.T()
+	product, err := q.Mul(qt)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	identity, _ := NewIdentity(n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if math.Abs(product.At(i, j)-identity.At(i, j)) > 1e-9 {
+				t.Fatalf("Q is not orthogonal at (%d,%d): %v", i, j, product.At(i, j))
+			}
+		}
+	}
+}
+
+func TestQRFactorization(t *testing.T) {
+	m, _ := New([][]float64{
+		{12, -51, 4},
+		{6, 167, -68},
+		{-4, 24, -41},
+	})
+
+	qr, err := m.QR()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	q := qr.Q()
+	isOrthogonal(t, q)
+
+	r := qr.R()
+	product, err := q.Mul(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			if math.Abs(product.At(i, j)-m.At(i, j)) > 1e-9 {
+				t.Errorf("QR does not reconstruct A at (%d,%d): got %v want %v", i, j, product.At(i, j), m.At(i, j))
+			}
+		}
+	}
+}
+
+func TestQRRUpperTriangular(t *testing.T) {
+	m, _ := New([][]float64{
+		{1, 2},
+		{3, 4},
+		{5, 6},
+	})
+
+	qr, err := m.QR()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := qr.R()
+	rows, cols := r.Dims()
+	for i := 0; i < rows; i++ {
+		for j := 0; j < cols; j++ {
+			if i > j && r.At(i, j) != 0 {
+				t.Error("R should be zero below the diagonal", i, j, r.At(i, j))
+			}
+		}
+	}
+}
+
+func TestQRNotEnoughRows(t *testing.T) {
+	m, _ := New([][]float64{{1, 2, 3}})
+	if _, err := m.QR(); err != errDimensionMismatch {
+		t.Error("expected errDimensionMismatch for a wide matrix")
+	}
+}
+
+func TestQRSolveLeastSquares(t *testing.T) {
+	// Fit y = a + bx to (0,1), (1,2), (2,3): an exact line, a=1, b=1.
+	a, _ := New([][]float64{{1, 0}, {1, 1}, {1, 2}})
+	qr, err := a.QR()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	x, err := qr.Solve([]float64{1, 2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []float64{1, 1}
+	for i := range want {
+		if math.Abs(x[i]-want[i]) > 1e-9 {
+			t.Error("unexpected result", x)
+		}
+	}
+}
+
+func TestQRBlockedMatchesUnblocked(t *testing.T) {
+	n := 6
+	data := make([][]float64, n)
+	for i := range data {
+		data[i] = make([]float64, n)
+		for j := range data[i] {
+			data[i][j] = float64((i+1)*3 + (j+1)*7%11 - i*j)
+		}
+	}
+	m, _ := New(data)
+
+	old := QRBlockSize
+	defer func() { QRBlockSize = old }()
+
+	QRBlockSize = 2
+	blocked, err := m.QR()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	QRBlockSize = n
+	unblocked, err := m.QR()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	br := blocked.R()
+	ur := unblocked.R()
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if math.Abs(br.At(i, j)-ur.At(i, j)) > 1e-9 {
+				t.Errorf("blocked and unblocked R disagree at (%d,%d): %v vs %v", i, j, br.At(i, j), ur.At(i, j))
+			}
+		}
+	}
+}